@@ -0,0 +1,178 @@
+package conf
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// bundleContents is what a signed config bundle unpacks into: config.toml
+// decoded separately since callers need to inspect it before deciding
+// whether to write anything, plus every other file keyed by its
+// repo-relative path (e.g. "store_data/conf/redis.conf", TLS material
+// referenced by LOG.CrtFile/KeyFile).
+type bundleContents struct {
+	configTOML []byte
+	files      map[string][]byte
+}
+
+// verifyAndExtractBundle checks the detached signature at bundlePath+".sig"
+// against trustedPubKey and unpacks the tar.gz bundle in memory. It does
+// not touch cpRepoPath; callers decide what to do with the result.
+func verifyAndExtractBundle(bundlePath string, trustedPubKey ed25519.PublicKey) (*bundleContents, error) {
+	archiveBytes, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("read bundle failed, path: %s, error: %w", bundlePath, err)
+	}
+
+	sigBytes, err := os.ReadFile(bundlePath + ".sig")
+	if err != nil {
+		return nil, fmt.Errorf("read bundle signature failed, path: %s, error: %w", bundlePath+".sig", err)
+	}
+
+	if !ed25519.Verify(trustedPubKey, archiveBytes, sigBytes) {
+		return nil, fmt.Errorf("bundle signature verification failed, path: %s", bundlePath)
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(archiveBytes))
+	if err != nil {
+		return nil, fmt.Errorf("bundle is not a valid gzip archive, path: %s, error: %w", bundlePath, err)
+	}
+	defer gzr.Close()
+
+	contents := &bundleContents{files: map[string][]byte{}}
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle tar entry failed, path: %s, error: %w", bundlePath, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !isSafeBundlePath(hdr.Name) {
+			return nil, fmt.Errorf("bundle entry %q escapes the target directory, refusing to extract", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read bundle file %s failed, error: %w", hdr.Name, err)
+		}
+		if hdr.Name == "config.toml" {
+			contents.configTOML = data
+		} else {
+			contents.files[hdr.Name] = data
+		}
+	}
+
+	if contents.configTOML == nil {
+		return nil, fmt.Errorf("bundle %s does not contain config.toml", bundlePath)
+	}
+	return contents, nil
+}
+
+// isSafeBundlePath rejects absolute paths and any path that would escape
+// cpRepoPath once joined (a "../../etc/cron.d/..." Zip-Slip entry), even
+// though the bundle is signature-verified: a compromised signer is a
+// different threat model than a malformed archive escaping its target
+// directory.
+func isSafeBundlePath(name string) bool {
+	if path.IsAbs(name) {
+		return false
+	}
+	cleaned := path.Clean(name)
+	return cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
+
+// LoadSignedBundle verifies and parses a signed config bundle without
+// writing anything to disk, so operators can inspect/dry-run a bundle
+// before pushing it out with GenerateRepoFromBundle.
+func LoadSignedBundle(path string, trustedPubKey ed25519.PublicKey) (*ComputeNode, error) {
+	contents, err := verifyAndExtractBundle(path, trustedPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("load signed bundle failed, error: %w", err)
+	}
+
+	var cfg ComputeNode
+	if _, err = toml.Decode(string(contents.configTOML), &cfg); err != nil {
+		return nil, fmt.Errorf("parse bundled config.toml failed, error: %w", err)
+	}
+	return &cfg, nil
+}
+
+// GenerateRepoFromBundle is the --from-bundle mode of GenerateRepo: it
+// verifies bundlePath against trustedPubKey, and extracts config.toml plus
+// every other bundled file (redis.conf, TLS material, ...) into cpRepoPath.
+// It refuses to overwrite an existing config.toml unless the bundle's
+// Version is strictly newer, so fleet operators can push vetted config to
+// many compute providers without worrying about clobbering a node that
+// already picked up a later revision. An existing config.toml that does
+// not parse as TOML has no Version to compare and is overwritten.
+func GenerateRepoFromBundle(cpRepoPath, bundlePath string, trustedPubKey ed25519.PublicKey) error {
+	contents, err := verifyAndExtractBundle(bundlePath, trustedPubKey)
+	if err != nil {
+		return fmt.Errorf("generate repo from bundle failed, error: %w", err)
+	}
+
+	var bundledConfig ComputeNode
+	if _, err = toml.Decode(string(contents.configTOML), &bundledConfig); err != nil {
+		return fmt.Errorf("parse bundled config.toml failed, error: %w", err)
+	}
+
+	configFilePath := path.Join(cpRepoPath, "config.toml")
+	if existing, err := os.ReadFile(configFilePath); err == nil {
+		var onDisk ComputeNode
+		if _, derr := toml.Decode(string(existing), &onDisk); derr == nil && bundledConfig.Version <= onDisk.Version {
+			return fmt.Errorf("refusing to overwrite config.toml: bundle version %d is not newer than on-disk version %d", bundledConfig.Version, onDisk.Version)
+		}
+	}
+
+	dataDir := path.Join(cpRepoPath, "store_data/data")
+	if err = os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+	confDir := path.Join(cpRepoPath, "store_data/conf")
+	if err = os.MkdirAll(confDir, 0755); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(configFilePath, contents.configTOML, 0644); err != nil {
+		return fmt.Errorf("write config.toml from bundle failed, error: %w", err)
+	}
+
+	for name, data := range contents.files {
+		// isSafeBundlePath already rejected escaping entries when the
+		// archive was unpacked in verifyAndExtractBundle.
+		dest := path.Join(cpRepoPath, name)
+		if err = os.MkdirAll(path.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("create dir for bundled file %s failed, error: %w", name, err)
+		}
+		if err = os.WriteFile(dest, data, bundleFileMode(name, &bundledConfig)); err != nil {
+			return fmt.Errorf("write bundled file %s failed, error: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// bundleFileMode returns 0600 for TLS private key material (LOG.KeyFile,
+// or anything that merely looks like a key file) so it isn't left
+// world-readable, and 0644 for everything else in the bundle.
+func bundleFileMode(name string, cfg *ComputeNode) os.FileMode {
+	if cfg.LOG.KeyFile != "" && name == cfg.LOG.KeyFile {
+		return 0600
+	}
+	if strings.Contains(strings.ToLower(path.Base(name)), "key") {
+		return 0600
+	}
+	return 0644
+}
@@ -0,0 +1,170 @@
+package conf
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ChainConfig describes one EVM-compatible network the compute provider can
+// talk to. It is parsed from a repeatable `[[Chain]]` TOML table, so new
+// networks can be registered without code changes.
+type ChainConfig struct {
+	Name       string
+	RPCUrls    []string
+	ChainID    int64
+	SwanToken  string
+	Collateral string
+	Explorer   string
+}
+
+// chainHealthyURL holds the first RPCUrls entry that answered the startup dial,
+// kept unexported because it's derived state rather than config.
+var (
+	chainRegistryMu sync.RWMutex
+	chainRegistry   = map[string]ChainConfig{}
+	chainHealthyURL = map[string]string{}
+)
+
+// buildChainRegistry rebuilds the in-memory chain registry from cfg.Chain,
+// plus a synthesized "swan" entry from the legacy RPC/CONTRACT fields so
+// config.toml files predating [[Chain]] tables keep resolving DefaultRpc.
+func buildChainRegistry(cfg *ComputeNode) {
+	registry := make(map[string]ChainConfig, len(cfg.Chain)+1)
+
+	if cfg.RPC.SwanTestnet != "" {
+		registry[DefaultRpc] = ChainConfig{
+			Name:       DefaultRpc,
+			RPCUrls:    []string{cfg.RPC.SwanTestnet},
+			SwanToken:  cfg.CONTRACT.SwanToken,
+			Collateral: cfg.CONTRACT.Collateral,
+		}
+	}
+
+	for _, chain := range cfg.Chain {
+		registry[chain.Name] = chain
+	}
+
+	chainRegistryMu.Lock()
+	chainRegistry = registry
+	// Drop any cached healthy endpoint that no longer appears in the
+	// rebuilt chain's RPCUrls: a hot reload can change a chain's RPCUrls
+	// while keeping the same Name, and a stale entry here would otherwise
+	// keep firstHealthyRPCURL returning an endpoint the new config no
+	// longer lists.
+	for name, healthy := range chainHealthyURL {
+		chain, ok := registry[name]
+		if !ok || !containsString(chain.RPCUrls, healthy) {
+			delete(chainHealthyURL, name)
+		}
+	}
+	chainRegistryMu.Unlock()
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// ListChains returns the names of every chain currently registered.
+func ListChains() []string {
+	chainRegistryMu.RLock()
+	defer chainRegistryMu.RUnlock()
+
+	names := make([]string, 0, len(chainRegistry))
+	for name := range chainRegistry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetChain returns the registered ChainConfig for name, or an error if no
+// such chain is registered, via [[Chain]] or the legacy RPC fields, in the
+// currently loaded config.
+func GetChain(name string) (ChainConfig, error) {
+	chainRegistryMu.RLock()
+	defer chainRegistryMu.RUnlock()
+
+	chain, ok := chainRegistry[name]
+	if !ok {
+		return ChainConfig{}, fmt.Errorf("chain %q is not registered", name)
+	}
+	return chain, nil
+}
+
+// firstHealthyRPCURL returns the endpoint the startup health check marked
+// reachable, falling back to the first configured URL when the health
+// check hasn't run yet (or every endpoint failed).
+func (c ChainConfig) firstHealthyRPCURL() string {
+	chainRegistryMu.RLock()
+	healthy, ok := chainHealthyURL[c.Name]
+	chainRegistryMu.RUnlock()
+	if ok {
+		return healthy
+	}
+	if len(c.RPCUrls) > 0 {
+		return c.RPCUrls[0]
+	}
+	return ""
+}
+
+// CheckChainsHealth dials every registered chain's RPCUrls in order and
+// records the first endpoint that accepts a TCP connection, so
+// GetRpcByName/firstHealthyRPCURL can skip dead primaries. InitConfig runs
+// it once at startup and reload runs it again after every successful hot
+// reload.
+func CheckChainsHealth(timeout time.Duration) {
+	chainRegistryMu.RLock()
+	chains := make([]ChainConfig, 0, len(chainRegistry))
+	for _, chain := range chainRegistry {
+		chains = append(chains, chain)
+	}
+	chainRegistryMu.RUnlock()
+
+	for _, chain := range chains {
+		var healthy string
+		for _, rpcURL := range chain.RPCUrls {
+			if dialRPC(rpcURL, timeout) {
+				healthy = rpcURL
+				break
+			}
+			log.Printf("chain %q RPC endpoint unreachable, trying next: %s", chain.Name, rpcURL)
+		}
+		if healthy == "" {
+			log.Printf("chain %q has no reachable RPC endpoint out of %d configured", chain.Name, len(chain.RPCUrls))
+			continue
+		}
+		chainRegistryMu.Lock()
+		chainHealthyURL[chain.Name] = healthy
+		chainRegistryMu.Unlock()
+	}
+}
+
+func dialRPC(rpcURL string, timeout time.Duration) bool {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return false
+	}
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "https", "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		default:
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	conn, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
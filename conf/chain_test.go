@@ -0,0 +1,32 @@
+package conf
+
+import "testing"
+
+// TestBuildChainRegistryPrunesStaleHealthyURL guards against a regression
+// where a hot reload that changed a chain's RPCUrls (keeping the same
+// Name) left the old healthy endpoint in chainHealthyURL, so
+// firstHealthyRPCURL kept returning an endpoint the new config no longer
+// lists.
+func TestBuildChainRegistryPrunesStaleHealthyURL(t *testing.T) {
+	cfg := &ComputeNode{Chain: []ChainConfig{
+		{Name: "swan", RPCUrls: []string{"http://old.example.com:8545"}},
+	}}
+	buildChainRegistry(cfg)
+
+	chainRegistryMu.Lock()
+	chainHealthyURL["swan"] = "http://old.example.com:8545"
+	chainRegistryMu.Unlock()
+
+	reloaded := &ComputeNode{Chain: []ChainConfig{
+		{Name: "swan", RPCUrls: []string{"http://new.example.com:8545"}},
+	}}
+	buildChainRegistry(reloaded)
+
+	chain, err := GetChain("swan")
+	if err != nil {
+		t.Fatalf("GetChain(\"swan\") failed: %v", err)
+	}
+	if got := chain.firstHealthyRPCURL(); got != "http://new.example.com:8545" {
+		t.Fatalf("firstHealthyRPCURL() = %q, want the new RPCUrls entry (stale healthy cache wasn't pruned)", got)
+	}
+}
@@ -4,14 +4,24 @@ import (
 	_ "embed"
 	"fmt"
 	"github.com/BurntSushi/toml"
-	"log"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
-var config *ComputeNode
+var configRef atomic.Value // holds *ComputeNode
+
+// cpConfigFile remembers the path InitConfig loaded from so StartWatch
+// can reload the same file without the caller repeating it.
+var cpConfigFile string
+
+// cpStandalone remembers the mode InitConfig was called with so reload()
+// validates a hot-reloaded config.toml against the same field set, instead
+// of always re-validating as a full (non-standalone) node.
+var cpStandalone bool
 
 const (
 	DefaultRpc = "swan"
@@ -19,6 +29,10 @@ const (
 
 // ComputeNode is a compute node config
 type ComputeNode struct {
+	// Version identifies the config revision. It is only meaningful to
+	// signed bundles (see bundle.go): GenerateRepoFromBundle refuses to
+	// overwrite an on-disk config.toml with a bundle that isn't newer.
+	Version  int
 	API      API
 	UBI      UBI
 	LOG      LOG
@@ -27,6 +41,7 @@ type ComputeNode struct {
 	Registry Registry
 	RPC      RPC
 	CONTRACT CONTRACT
+	Chain    []ChainConfig
 }
 
 type API struct {
@@ -75,6 +90,10 @@ type Registry struct {
 type RPC struct {
 	SwanTestnet string `toml:"SWAN_TESTNET"`
 	SwanMainnet string `toml:"SWAN_MAINNET"`
+	// DefaultChain selects the Chain entry GetRpcByName(DefaultRpc) resolves
+	// to. It falls back to the const DefaultRpc when left empty, so existing
+	// config.toml files keep working unchanged.
+	DefaultChain string
 }
 
 type CONTRACT struct {
@@ -82,110 +101,56 @@ type CONTRACT struct {
 	Collateral string `toml:"SWAN_COLLATERAL_CONTRACT"`
 }
 
+// GetRpcByName resolves an RPC endpoint by chain name, preferring the
+// [[Chain]]-table registry (see chain.go) and falling back to the legacy
+// RPC/CONTRACT fields for "swan" so older config.toml files keep working.
+// Resolving rpcName == DefaultRpc is redirected to RPC.DefaultChain when
+// that field is set, so RPC.DefaultChain makes DefaultRpc a selectable
+// field instead of always meaning "swan".
 func GetRpcByName(rpcName string) (string, error) {
+	resolvedName := rpcName
+	if rpcName == DefaultRpc {
+		if defaultChain := GetConfig().RPC.DefaultChain; defaultChain != "" {
+			resolvedName = defaultChain
+		}
+	}
+
+	if chain, err := GetChain(resolvedName); err == nil {
+		return chain.firstHealthyRPCURL(), nil
+	}
+
 	var rpc string
 	switch rpcName {
 	case DefaultRpc:
 		rpc = GetConfig().RPC.SwanTestnet
-		break
 	}
 	return rpc, nil
 }
 
 func InitConfig(cpRepoPath string, standalone bool) error {
 	configFile := filepath.Join(cpRepoPath, "config.toml")
-	metaData, err := toml.DecodeFile(configFile, &config)
-	if err != nil {
+	var newConfig ComputeNode
+	if _, err := toml.DecodeFile(configFile, &newConfig); err != nil {
 		return fmt.Errorf("failed load config file, path: %s, error: %w", configFile, err)
 	}
-	if standalone {
-		if !requiredFieldsAreGivenForSeparate(metaData) {
-			log.Fatal("Required fields not given")
-		}
-	} else {
-		if !requiredFieldsAreGiven(metaData) {
-			log.Fatal("Required fields not given")
-		}
+	if err := applyOverlay(&newConfig); err != nil {
+		return fmt.Errorf("apply env/secret overlay failed, error: %w", err)
 	}
-	return nil
-}
-
-func GetConfig() *ComputeNode {
-	return config
-}
-
-func requiredFieldsAreGiven(metaData toml.MetaData) bool {
-	requiredFields := [][]string{
-		{"API"},
-		{"LOG"},
-		{"UBI"},
-		{"HUB"},
-		{"MCS"},
-		{"Registry"},
-		{"RPC"},
-		{"CONTRACT"},
-
-		{"API", "MultiAddress"},
-		{"API", "Domain"},
-		{"API", "RedisUrl"},
-
-		{"LOG", "CrtFile"},
-		{"LOG", "KeyFile"},
-
-		{"UBI", "UbiTask"},
-		{"UBI", "UbiEnginePk"},
-		{"UBI", "UbiUrl"},
-
-		{"HUB", "ServerUrl"},
-		{"HUB", "AccessToken"},
-		{"HUB", "WalletAddress"},
-
-		{"MCS", "ApiKey"},
-		{"MCS", "BucketName"},
-		{"MCS", "Network"},
-		{"MCS", "FileCachePath"},
-
-		{"RPC", "SWAN_TESTNET"},
-
-		{"CONTRACT", "SWAN_CONTRACT"},
-		{"CONTRACT", "SWAN_COLLATERAL_CONTRACT"},
-	}
-
-	for _, v := range requiredFields {
-		if !metaData.IsDefined(v...) {
-			log.Fatal("Required fields ", v)
-		}
+	if err := validate(&newConfig, standalone); err != nil {
+		return fmt.Errorf("config validation failed, path: %s, error: %w", configFile, err)
 	}
 
-	return true
+	cpConfigFile = configFile
+	cpStandalone = standalone
+	configRef.Store(&newConfig)
+	buildChainRegistry(&newConfig)
+	go CheckChainsHealth(5 * time.Second)
+	return nil
 }
 
-func requiredFieldsAreGivenForSeparate(metaData toml.MetaData) bool {
-	requiredFields := [][]string{
-		{"API"},
-		{"UBI"},
-		{"HUB"},
-
-		{"API", "MultiAddress"},
-		{"API", "RedisUrl"},
-
-		{"UBI", "UbiTask"},
-		{"UBI", "UbiEnginePk"},
-		{"UBI", "UbiUrl"},
-
-		{"RPC", "SWAN_TESTNET"},
-
-		{"CONTRACT", "SWAN_CONTRACT"},
-		{"CONTRACT", "SWAN_COLLATERAL_CONTRACT"},
-	}
-
-	for _, v := range requiredFields {
-		if !metaData.IsDefined(v...) {
-			log.Fatal("Required fields ", v)
-		}
-	}
-
-	return true
+func GetConfig() *ComputeNode {
+	cfg, _ := configRef.Load().(*ComputeNode)
+	return cfg
 }
 
 //go:embed config.toml
@@ -217,12 +182,11 @@ func GenerateRepo(cpRepoPath string) error {
 
 	redisConfigFilePath := path.Join(confDir, "redis.conf")
 	if _, err = os.Stat(redisConfigFilePath); os.IsNotExist(err) {
-		redisConfigFile, err := os.Create(redisConfigFilePath)
+		renderedRedisConfig, err := renderRedisConfig(DefaultRedisOptions())
 		if err != nil {
-			return fmt.Errorf("create redis config file failed, error: %v", err)
+			return fmt.Errorf("render redis config failed, error: %v", err)
 		}
-		defer redisConfigFile.Close()
-		if _, err = redisConfigFile.WriteString(redisConfigFileContent); err != nil {
+		if err = os.WriteFile(redisConfigFilePath, []byte(renderedRedisConfig), 0644); err != nil {
 			return fmt.Errorf("write redis config file failed, error: %v", err)
 		}
 	}
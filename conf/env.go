@@ -0,0 +1,129 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// envPrefix namespaces every environment variable this package reads, e.g.
+// CP_HUB_ACCESSTOKEN overrides ComputeNode.HUB.AccessToken.
+const envPrefix = "CP_"
+
+// secretFieldNames marks the (Section, Field) pairs whose values must never
+// be printed verbatim by DumpRedacted or log output, because they are
+// credentials rather than plain settings.
+var secretFieldNames = map[string]bool{
+	"HUB.AccessToken":     true,
+	"MCS.ApiKey":          true,
+	"MCS.AccessToken":     true,
+	"Registry.Password":   true,
+	"API.RedisPassword":   true,
+	"API.WalletWhiteList": true,
+	"UBI.UbiEnginePk":     true,
+	"HUB.OrchestratorPk":  true,
+}
+
+const redacted = "***REDACTED***"
+
+// applyOverlay layers environment variables and then `*_FILE` secret file
+// references on top of cfg, following the naming convention
+// CP_<SECTION>_<FIELD> (e.g. CP_HUB_ACCESSTOKEN, CP_REGISTRY_PASSWORD).
+// A `_FILE` suffixed variable (CP_MCS_APIKEY_FILE) takes precedence over its
+// plain counterpart and is read as a path whose trimmed contents become the
+// field's value, mirroring how 1Panel/k3s keep secrets out of on-disk
+// config. Only exported string/int/bool/float64 fields are overlaid.
+func applyOverlay(cfg *ComputeNode) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sectionField := v.Field(i)
+		sectionName := t.Field(i).Name
+		if sectionField.Kind() != reflect.Struct {
+			continue
+		}
+		sectionType := sectionField.Type()
+		for j := 0; j < sectionType.NumField(); j++ {
+			fieldName := sectionType.Field(j).Name
+			envName := envPrefix + strings.ToUpper(sectionName) + "_" + strings.ToUpper(fieldName)
+
+			value, ok := os.LookupEnv(envName)
+			if filePath, hasFile := os.LookupEnv(envName + "_FILE"); hasFile {
+				content, err := os.ReadFile(filePath)
+				if err != nil {
+					return fmt.Errorf("read secret file for %s failed, path: %s, error: %w", envName, filePath, err)
+				}
+				value = strings.TrimSpace(string(content))
+				ok = true
+			}
+			if !ok {
+				continue
+			}
+
+			if err := setField(sectionField.Field(j), value); err != nil {
+				return fmt.Errorf("apply %s failed: %w", envName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field kind: %s", field.Kind())
+	}
+	return nil
+}
+
+// DumpRedacted returns a copy of the active config with every string field
+// in secretFieldNames replaced by the redacted marker, safe to log or print
+// for debugging.
+func DumpRedacted() *ComputeNode {
+	cfg := GetConfig()
+	if cfg == nil {
+		return nil
+	}
+	redactedCfg := *cfg
+
+	v := reflect.ValueOf(&redactedCfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sectionField := v.Field(i)
+		sectionName := t.Field(i).Name
+		if sectionField.Kind() != reflect.Struct {
+			continue
+		}
+		sectionType := sectionField.Type()
+		for j := 0; j < sectionType.NumField(); j++ {
+			key := sectionName + "." + sectionType.Field(j).Name
+			if secretFieldNames[key] && sectionField.Field(j).Kind() == reflect.String {
+				sectionField.Field(j).SetString(redacted)
+			}
+		}
+	}
+	return &redactedCfg
+}
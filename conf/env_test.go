@@ -0,0 +1,92 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyOverlayPrecedenceTOMLOnly(t *testing.T) {
+	cfg := &ComputeNode{HUB: HUB{AccessToken: "from-toml"}}
+	if err := applyOverlay(cfg); err != nil {
+		t.Fatalf("applyOverlay returned an error: %v", err)
+	}
+	if cfg.HUB.AccessToken != "from-toml" {
+		t.Fatalf("HUB.AccessToken = %q, want %q (unchanged, no env set)", cfg.HUB.AccessToken, "from-toml")
+	}
+}
+
+func TestApplyOverlayEnvOverridesTOML(t *testing.T) {
+	t.Setenv("CP_HUB_ACCESSTOKEN", "from-env")
+
+	cfg := &ComputeNode{HUB: HUB{AccessToken: "from-toml"}}
+	if err := applyOverlay(cfg); err != nil {
+		t.Fatalf("applyOverlay returned an error: %v", err)
+	}
+	if cfg.HUB.AccessToken != "from-env" {
+		t.Fatalf("HUB.AccessToken = %q, want %q", cfg.HUB.AccessToken, "from-env")
+	}
+}
+
+func TestApplyOverlaySecretFileOverridesEnv(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretPath, []byte("  from-secret-file  \n"), 0600); err != nil {
+		t.Fatalf("write secret file: %v", err)
+	}
+
+	t.Setenv("CP_HUB_ACCESSTOKEN", "from-env")
+	t.Setenv("CP_HUB_ACCESSTOKEN_FILE", secretPath)
+
+	cfg := &ComputeNode{HUB: HUB{AccessToken: "from-toml"}}
+	if err := applyOverlay(cfg); err != nil {
+		t.Fatalf("applyOverlay returned an error: %v", err)
+	}
+	if cfg.HUB.AccessToken != "from-secret-file" {
+		t.Fatalf("HUB.AccessToken = %q, want %q (secret file should win and be trimmed)", cfg.HUB.AccessToken, "from-secret-file")
+	}
+}
+
+func TestApplyOverlayConvertsNonStringFields(t *testing.T) {
+	t.Setenv("CP_API_PORT", "9090")
+	t.Setenv("CP_UBI_UBITASK", "true")
+	t.Setenv("CP_HUB_BALANCETHRESHOLD", "1.5")
+
+	cfg := &ComputeNode{}
+	if err := applyOverlay(cfg); err != nil {
+		t.Fatalf("applyOverlay returned an error: %v", err)
+	}
+	if cfg.API.Port != 9090 {
+		t.Fatalf("API.Port = %d, want 9090", cfg.API.Port)
+	}
+	if !cfg.UBI.UbiTask {
+		t.Fatal("UBI.UbiTask = false, want true")
+	}
+	if cfg.HUB.BalanceThreshold != 1.5 {
+		t.Fatalf("HUB.BalanceThreshold = %v, want 1.5", cfg.HUB.BalanceThreshold)
+	}
+}
+
+func TestApplyOverlayMissingSecretFileErrors(t *testing.T) {
+	t.Setenv("CP_HUB_ACCESSTOKEN_FILE", filepath.Join(t.TempDir(), "does-not-exist"))
+
+	cfg := &ComputeNode{}
+	if err := applyOverlay(cfg); err == nil {
+		t.Fatal("expected applyOverlay to fail when a *_FILE secret reference doesn't exist")
+	}
+}
+
+func TestDumpRedactedHidesSecretFields(t *testing.T) {
+	cfg := &ComputeNode{HUB: HUB{AccessToken: "super-secret", ServerUrl: "https://hub.example.com"}}
+	configRef.Store(cfg)
+
+	redactedCfg := DumpRedacted()
+	if redactedCfg.HUB.AccessToken != redacted {
+		t.Fatalf("HUB.AccessToken = %q, want redacted", redactedCfg.HUB.AccessToken)
+	}
+	if redactedCfg.HUB.ServerUrl != "https://hub.example.com" {
+		t.Fatalf("HUB.ServerUrl was unexpectedly modified: %q", redactedCfg.HUB.ServerUrl)
+	}
+	if cfg.HUB.AccessToken != "super-secret" {
+		t.Fatal("DumpRedacted must not mutate the live config")
+	}
+}
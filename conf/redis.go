@@ -0,0 +1,196 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOptions is the subset of redis.conf directives the compute provider
+// manages directly. Other directives in the embedded template are left
+// untouched.
+type RedisOptions struct {
+	Port            int
+	Bind            string
+	RequirePass     string
+	MaxMemory       string
+	MaxMemoryPolicy string
+	AppendOnly      bool
+	Timeout         int
+}
+
+// liveUpdatableRedisDirectives are the keys Redis accepts via `CONFIG SET`
+// without a restart, restricted to the directives RedisOptions actually
+// exposes. Everything else in RedisOptions (port, bind, requirepass,
+// appendonly) only takes effect the next time redis-server reads
+// redis.conf.
+var liveUpdatableRedisDirectives = map[string]bool{
+	"timeout":          true,
+	"maxmemory":        true,
+	"maxmemory-policy": true,
+}
+
+// knownRedisDirectives is the allow-list validateRedisDirectives checks the
+// directives renderRedisConfig manages against, so a typo in that map's
+// keys can't silently ship a directive redis-server would refuse to start
+// with. It intentionally does NOT cover the whole embedded redis.conf
+// template: that file owns plenty of directives (databases, save,
+// appendfsync, ...) this code never touches, and rejecting those would
+// fail GenerateRepo for every init.
+var knownRedisDirectives = map[string]bool{
+	"port": true, "bind": true, "requirepass": true, "maxmemory": true,
+	"maxmemory-policy": true, "appendonly": true, "timeout": true,
+}
+
+// DefaultRedisOptions mirrors the directives shipped in the embedded
+// redis.conf template.
+func DefaultRedisOptions() RedisOptions {
+	return RedisOptions{
+		Port:            6379,
+		Bind:            "127.0.0.1",
+		MaxMemory:       "256mb",
+		MaxMemoryPolicy: "noeviction",
+		AppendOnly:      false,
+		Timeout:         0,
+	}
+}
+
+// renderRedisConfig overlays opts onto the embedded redis.conf template,
+// replacing each directive's existing line or appending it if absent. It
+// returns an error, before rendering, if a managed directive is outside
+// knownRedisDirectives; the template's other directives are not checked.
+func renderRedisConfig(opts RedisOptions) (string, error) {
+	directives := map[string]string{
+		"port":             strconv.Itoa(opts.Port),
+		"maxmemory":        opts.MaxMemory,
+		"maxmemory-policy": opts.MaxMemoryPolicy,
+		"appendonly":       boolToYesNo(opts.AppendOnly),
+		"timeout":          strconv.Itoa(opts.Timeout),
+	}
+	if opts.Bind != "" {
+		directives["bind"] = opts.Bind
+	}
+	if opts.RequirePass != "" {
+		directives["requirepass"] = opts.RequirePass
+	}
+
+	if err := validateRedisDirectives(directives); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(redisConfigFileContent, "\n")
+	for key, value := range directives {
+		line := fmt.Sprintf("%s %s", key, value)
+		if i := findDirectiveLine(lines, key); i >= 0 {
+			lines[i] = line
+		} else {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func findDirectiveLine(lines []string, key string) int {
+	for i, line := range lines {
+		fields := strings.Fields(strings.TrimSpace(line))
+		if len(fields) > 0 && strings.EqualFold(fields[0], key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateRedisDirectives rejects any key in directives that isn't in
+// knownRedisDirectives, before renderRedisConfig writes it out.
+func validateRedisDirectives(directives map[string]string) error {
+	var unknown []string
+	for key := range directives {
+		if !knownRedisDirectives[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown redis directives: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func boolToYesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// UpdateRedisConfig rewrites redis.conf under cpRepoPath from opts and, if
+// the compute provider is the one running the Redis process (API.RedisUrl
+// points at this same instance), pushes the directives that Redis supports
+// live via CONFIG SET so operators don't need to restart it for a tuning
+// change. The rewritten redis.conf is itself the persisted desired state:
+// a future restart of redis-server picks it up unchanged.
+func UpdateRedisConfig(cpRepoPath string, opts RedisOptions) error {
+	rendered, err := renderRedisConfig(opts)
+	if err != nil {
+		return fmt.Errorf("render redis config failed, error: %w", err)
+	}
+
+	redisConfigFilePath := path.Join(cpRepoPath, "store_data/conf/redis.conf")
+	if err = os.WriteFile(redisConfigFilePath, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("write redis config file failed, path: %s, error: %w", redisConfigFilePath, err)
+	}
+
+	if ownsRedisProcess(opts) {
+		if err = applyLiveRedisConfig(opts); err != nil {
+			log.Printf("apply live redis config failed, changes take effect on next restart, error: %v", err)
+		}
+	}
+	return nil
+}
+
+// ownsRedisProcess decides whether CONFIG SET is issued against the
+// configured API.RedisUrl: it reports true when that URL contains
+// "127.0.0.1" or "localhost". opts is not compared against the URL, so the
+// port and bind address in RedisOptions play no part in the decision.
+func ownsRedisProcess(opts RedisOptions) bool {
+	cfg := GetConfig()
+	if cfg == nil || cfg.API.RedisUrl == "" {
+		return false
+	}
+	return strings.Contains(cfg.API.RedisUrl, "127.0.0.1") ||
+		strings.Contains(cfg.API.RedisUrl, "localhost")
+}
+
+func applyLiveRedisConfig(opts RedisOptions) error {
+	cfg := GetConfig()
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.API.RedisUrl,
+		Password: cfg.API.RedisPassword,
+	})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	live := map[string]string{
+		"maxmemory":        opts.MaxMemory,
+		"maxmemory-policy": opts.MaxMemoryPolicy,
+		"timeout":          strconv.Itoa(opts.Timeout),
+	}
+	for key, value := range live {
+		if !liveUpdatableRedisDirectives[key] || value == "" {
+			continue
+		}
+		if err := client.Do(ctx, "CONFIG", "SET", key, value).Err(); err != nil {
+			return fmt.Errorf("CONFIG SET %s %s failed, error: %w", key, value, err)
+		}
+	}
+	return nil
+}
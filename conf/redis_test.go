@@ -0,0 +1,97 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderRedisConfigAppliesManagedDirectives(t *testing.T) {
+	opts := RedisOptions{
+		Port:            6380,
+		Bind:            "0.0.0.0",
+		RequirePass:     "s3cr3t",
+		MaxMemory:       "512mb",
+		MaxMemoryPolicy: "allkeys-lru",
+		AppendOnly:      true,
+		Timeout:         30,
+	}
+
+	rendered, err := renderRedisConfig(opts)
+	if err != nil {
+		t.Fatalf("renderRedisConfig returned an error: %v", err)
+	}
+
+	want := map[string]string{
+		"port":             "6380",
+		"bind":             "0.0.0.0",
+		"requirepass":      "s3cr3t",
+		"maxmemory":        "512mb",
+		"maxmemory-policy": "allkeys-lru",
+		"appendonly":       "yes",
+		"timeout":          "30",
+	}
+	lines := strings.Split(rendered, "\n")
+	for key, value := range want {
+		i := findDirectiveLine(lines, key)
+		if i < 0 {
+			t.Fatalf("rendered config is missing directive %q", key)
+		}
+		got := strings.Fields(lines[i])
+		if len(got) != 2 || got[1] != value {
+			t.Fatalf("directive %q = %q, want value %q", key, lines[i], value)
+		}
+	}
+}
+
+func TestRenderRedisConfigReplacesExistingDirectiveInPlace(t *testing.T) {
+	before := strings.Count(redisConfigFileContent, "\n")
+
+	rendered, err := renderRedisConfig(DefaultRedisOptions())
+	if err != nil {
+		t.Fatalf("renderRedisConfig returned an error: %v", err)
+	}
+	again, err := renderRedisConfig(DefaultRedisOptions())
+	if err != nil {
+		t.Fatalf("second renderRedisConfig call returned an error: %v", err)
+	}
+
+	// Each managed directive must end up on exactly one line: a line the
+	// template already has is replaced, not followed by an appended copy.
+	for _, key := range []string{"port", "bind", "maxmemory", "maxmemory-policy", "appendonly", "timeout"} {
+		n := 0
+		for _, line := range strings.Split(rendered, "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 && strings.EqualFold(fields[0], key) {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Fatalf("directive %q appears on %d lines, want exactly 1", key, n)
+		}
+	}
+
+	// Rendering twice with the same options must give the same output size.
+	if strings.Count(rendered, "\n") != strings.Count(again, "\n") {
+		t.Fatalf("re-rendering grew the file: %d lines vs %d lines", strings.Count(rendered, "\n"), strings.Count(again, "\n"))
+	}
+	if before > strings.Count(rendered, "\n") {
+		t.Fatalf("rendered config has fewer lines than the template")
+	}
+}
+
+func TestValidateRedisDirectivesRejectsUnknownKey(t *testing.T) {
+	err := validateRedisDirectives(map[string]string{"not-a-directive": "1"})
+	if err == nil {
+		t.Fatal("expected validateRedisDirectives to reject an unrecognized key")
+	}
+}
+
+func TestValidateRedisDirectivesAllowsManagedKeys(t *testing.T) {
+	err := validateRedisDirectives(map[string]string{
+		"port": "6379", "bind": "127.0.0.1", "requirepass": "x",
+		"maxmemory": "1mb", "maxmemory-policy": "noeviction",
+		"appendonly": "no", "timeout": "0",
+	})
+	if err != nil {
+		t.Fatalf("expected validateRedisDirectives to allow managed keys, got: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package conf
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// fieldSpec declares one schema entry: the dotted path into ComputeNode
+// (e.g. "API.Port"), whether it's required in each init mode, and an
+// optional semantic check run once the field is present.
+type fieldSpec struct {
+	path               string
+	requiredFull       bool
+	requiredStandalone bool
+	// check receives the already-decoded config so it can validate a field
+	// in the context of the whole document (e.g. cross-field rules).
+	check func(cfg *ComputeNode) error
+}
+
+var walletAddressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// configSchema replaces the old requiredFieldsAreGiven/requiredFieldsAreGivenForSeparate
+// field lists with a single declarative table, so validation errors can be
+// aggregated and reported together instead of exiting on the first miss.
+var configSchema = []fieldSpec{
+	{path: "API.MultiAddress", requiredFull: true, requiredStandalone: true, check: func(cfg *ComputeNode) error {
+		if _, err := ma.NewMultiaddr(cfg.API.MultiAddress); err != nil {
+			return fmt.Errorf("API.MultiAddress is not a valid multiaddr: %w", err)
+		}
+		return nil
+	}},
+	{path: "API.Domain", requiredFull: true},
+	{path: "API.RedisUrl", requiredFull: true, requiredStandalone: true},
+	{path: "API.Port", requiredFull: true, check: func(cfg *ComputeNode) error {
+		if cfg.API.Port <= 0 || cfg.API.Port > 65535 {
+			return fmt.Errorf("API.Port must be in range 1-65535, got %d", cfg.API.Port)
+		}
+		return nil
+	}},
+
+	{path: "LOG.CrtFile", requiredFull: true},
+	{path: "LOG.KeyFile", requiredFull: true},
+
+	{path: "UBI.UbiTask", requiredFull: true, requiredStandalone: true},
+	{path: "UBI.UbiEnginePk", requiredFull: true, requiredStandalone: true},
+	{path: "UBI.UbiUrl", requiredFull: true, requiredStandalone: true},
+
+	{path: "HUB.ServerUrl", requiredFull: true},
+	{path: "HUB.AccessToken", requiredFull: true},
+	{path: "HUB.WalletAddress", requiredFull: true, check: func(cfg *ComputeNode) error {
+		if !walletAddressRe.MatchString(cfg.HUB.WalletAddress) {
+			return fmt.Errorf("HUB.WalletAddress is not a valid 0x address: %q", cfg.HUB.WalletAddress)
+		}
+		return nil
+	}},
+
+	{path: "MCS.ApiKey", requiredFull: true},
+	{path: "MCS.BucketName", requiredFull: true},
+	{path: "MCS.FileCachePath", requiredFull: true},
+	{path: "MCS.Network", requiredFull: true, check: func(cfg *ComputeNode) error {
+		switch cfg.MCS.Network {
+		case "mainnet", "testnet":
+			return nil
+		default:
+			return fmt.Errorf("MCS.Network must be mainnet or testnet, got %q", cfg.MCS.Network)
+		}
+	}},
+
+	{path: "Registry.ServerAddress", check: func(cfg *ComputeNode) error {
+		if cfg.Registry.ServerAddress == "" {
+			return nil
+		}
+		if !isValidRegistryAddress(cfg.Registry.ServerAddress) {
+			return fmt.Errorf("Registry.ServerAddress is not a valid host[:port] or URL: %q", cfg.Registry.ServerAddress)
+		}
+		return nil
+	}},
+
+	{path: "RPC.SwanTestnet", requiredFull: true, requiredStandalone: true},
+
+	{path: "CONTRACT.SwanToken", requiredFull: true, requiredStandalone: true},
+	{path: "CONTRACT.Collateral", requiredFull: true, requiredStandalone: true},
+}
+
+// validationErrors aggregates every schema violation found in one pass so
+// callers see the whole picture instead of bailing out on the first one.
+type validationErrors []error
+
+func (v validationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, err := range v {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d validation error(s): %s", len(v), strings.Join(msgs, "; "))
+}
+
+// validate runs configSchema against cfg. standalone selects the reduced
+// set of fields that apply in standalone mode, mirroring the old
+// requiredFieldsAreGivenForSeparate field list.
+func validate(cfg *ComputeNode, standalone bool) error {
+	var errs validationErrors
+
+	for _, spec := range configSchema {
+		required := spec.requiredFull
+		if standalone {
+			required = spec.requiredStandalone
+		}
+
+		present := fieldIsSet(cfg, spec.path)
+		if required && !present {
+			errs = append(errs, fmt.Errorf("%s is required", spec.path))
+			continue
+		}
+		if !present || spec.check == nil {
+			continue
+		}
+		if err := spec.check(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate runs the full (non-standalone) schema against cfg, so callers
+// like `computing-provider init` can dry-run a config before writing it.
+func Validate(cfg *ComputeNode) error {
+	return validate(cfg, false)
+}
+
+// CheckRPCReachability dials cfg.RPC.SwanTestnet and reports whether it
+// answered within timeout. Unlike the schema above, this is never run
+// automatically by InitConfig or hot reload: a momentarily unreachable RPC
+// endpoint shouldn't keep a node from starting or from picking up an
+// otherwise-valid config change. Callers that want to surface connectivity
+// problems (e.g. `computing-provider init --check-rpc`) can invoke it
+// explicitly and treat a non-nil error as a warning.
+func CheckRPCReachability(cfg *ComputeNode, timeout time.Duration) error {
+	if !dialRPC(cfg.RPC.SwanTestnet, timeout) {
+		return fmt.Errorf("RPC.SwanTestnet %q is not reachable", cfg.RPC.SwanTestnet)
+	}
+	return nil
+}
+
+// isValidRegistryAddress accepts the address forms docker registries are
+// conventionally configured with: a bare "host:port" or "host" (no scheme,
+// the form most container registries document, e.g. "localhost:5000" or
+// "harbor.mycompany.com:443"), or a full URL with a scheme. url.Parse alone
+// rejects the bare form: without "//" it treats "host:port" as scheme
+// "host" with opaque data "port", leaving Host empty.
+func isValidRegistryAddress(addr string) bool {
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		return err == nil && u.Host != ""
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host != ""
+	}
+	// No port: treat the whole string as a bare hostname.
+	return !strings.ContainsAny(addr, " /\\")
+}
+
+// fieldIsSet reports whether the "Section.Field" path holds a non-zero
+// value in cfg. Bool fields are always considered set: `false` is a
+// legitimate configured value (e.g. UBI.UbiTask), not a missing one, so
+// zero-value presence detection can't distinguish "unset" from "off" for
+// them.
+func fieldIsSet(cfg *ComputeNode, path string) bool {
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	v := reflect.ValueOf(cfg).Elem()
+	section := v.FieldByName(parts[0])
+	if !section.IsValid() {
+		return false
+	}
+	field := section.FieldByName(parts[1])
+	if !field.IsValid() {
+		return false
+	}
+	if field.Kind() == reflect.Bool {
+		return true
+	}
+	return !field.IsZero()
+}
@@ -0,0 +1,117 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func validComputeNode() *ComputeNode {
+	return &ComputeNode{
+		API: API{
+			Port:         8085,
+			MultiAddress: "/ip4/127.0.0.1/tcp/8085",
+			Domain:       "example.com",
+			RedisUrl:     "127.0.0.1:6379",
+		},
+		LOG: LOG{
+			CrtFile: "/etc/cp/server.crt",
+			KeyFile: "/etc/cp/server.key",
+		},
+		UBI: UBI{
+			UbiTask:     false,
+			UbiEnginePk: "pk",
+			UbiUrl:      "https://ubi.example.com",
+		},
+		HUB: HUB{
+			ServerUrl:     "https://hub.example.com",
+			AccessToken:   "token",
+			WalletAddress: "0x" + strings.Repeat("1", 40),
+		},
+		MCS: MCS{
+			ApiKey:        "key",
+			BucketName:    "bucket",
+			Network:       "testnet",
+			FileCachePath: "/tmp/cache",
+		},
+		RPC: RPC{
+			SwanTestnet: "https://rpc.example.com",
+		},
+		CONTRACT: CONTRACT{
+			SwanToken:  "0xaaaa",
+			Collateral: "0xbbbb",
+		},
+	}
+}
+
+func TestValidatePassesOnWellFormedConfig(t *testing.T) {
+	if err := validate(validComputeNode(), false); err != nil {
+		t.Fatalf("validate() on a well-formed config returned an error: %v", err)
+	}
+}
+
+func TestValidatePassesOnWellFormedStandaloneConfig(t *testing.T) {
+	if err := validate(validComputeNode(), true); err != nil {
+		t.Fatalf("validate() in standalone mode on a well-formed config returned an error: %v", err)
+	}
+}
+
+// TestValidateRejectsMissingSwanFields checks that the RPC.SwanTestnet
+// schema entry, which fieldIsSet resolves by Go struct field name rather
+// than by its TOML tag (SWAN_TESTNET), is reported when left empty. That a
+// valid config is not reported as missing is covered by
+// TestValidatePassesOnWellFormedConfig.
+func TestValidateRejectsMissingSwanFields(t *testing.T) {
+	cfg := validComputeNode()
+	cfg.RPC.SwanTestnet = ""
+	if err := validate(cfg, false); err == nil {
+		t.Fatal("expected validate() to reject a config missing RPC.SwanTestnet")
+	}
+}
+
+// TestValidateAllowsFalseBoolField guards against treating a required bool
+// field explicitly set to false as "missing".
+func TestValidateAllowsFalseBoolField(t *testing.T) {
+	cfg := validComputeNode()
+	cfg.UBI.UbiTask = false
+	if err := validate(cfg, false); err != nil {
+		t.Fatalf("UBI.UbiTask = false should be valid, got error: %v", err)
+	}
+}
+
+func TestValidateRejectsBadWalletAddress(t *testing.T) {
+	cfg := validComputeNode()
+	cfg.HUB.WalletAddress = "not-an-address"
+	if err := validate(cfg, false); err == nil {
+		t.Fatal("expected validate() to reject a malformed HUB.WalletAddress")
+	}
+}
+
+func TestValidateRejectsBadMCSNetwork(t *testing.T) {
+	cfg := validComputeNode()
+	cfg.MCS.Network = "devnet"
+	if err := validate(cfg, false); err == nil {
+		t.Fatal("expected validate() to reject an MCS.Network outside mainnet/testnet")
+	}
+}
+
+// TestValidateAcceptsBareHostRegistryAddress guards against a regression
+// where Registry.ServerAddress was validated with url.Parse alone, which
+// rejects the conventional bare "host:port" form docker registries are
+// configured with (it parses as scheme "localhost" with no Host).
+func TestValidateAcceptsBareHostRegistryAddress(t *testing.T) {
+	for _, addr := range []string{"localhost:5000", "harbor.mycompany.com:443", "registry.example.com"} {
+		cfg := validComputeNode()
+		cfg.Registry.ServerAddress = addr
+		if err := validate(cfg, false); err != nil {
+			t.Fatalf("validate() rejected Registry.ServerAddress %q: %v", addr, err)
+		}
+	}
+}
+
+func TestValidateRejectsMalformedRegistryAddress(t *testing.T) {
+	cfg := validComputeNode()
+	cfg.Registry.ServerAddress = "not a host"
+	if err := validate(cfg, false); err == nil {
+		t.Fatal("expected validate() to reject a malformed Registry.ServerAddress")
+	}
+}
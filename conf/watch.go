@@ -0,0 +1,116 @@
+package conf
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ChangeHandler is notified with the previous and the newly activated
+// config whenever a hot reload succeeds. old is the config that was active
+// before the reload (the one InitConfig loaded, or an earlier reload).
+type ChangeHandler func(old, new *ComputeNode)
+
+var (
+	watcherMu   sync.Mutex
+	subscribers []ChangeHandler
+)
+
+// OnChange registers a handler that is invoked after a config reload has
+// been validated and swapped in. Handlers run synchronously, in
+// registration order, on the goroutine that detected the change.
+func OnChange(handler ChangeHandler) {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+	subscribers = append(subscribers, handler)
+}
+
+// StartWatch watches config.toml for changes and reloads it on the fly,
+// so subsystems like the API server, MCS client, Redis pool and HUB
+// orchestrator connection can pick up new settings without a restart.
+// It also reloads on SIGHUP, the conventional "reload config" signal.
+// The caller is expected to have already called InitConfig once; StartWatch
+// only reacts to subsequent changes.
+func StartWatch(cpRepoPath string) error {
+	if cpConfigFile == "" {
+		return fmt.Errorf("config has not been loaded yet, call InitConfig first")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create config watcher failed, error: %w", err)
+	}
+	if err = watcher.Add(cpRepoPath); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch config dir: %s failed, error: %w", cpRepoPath, err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Name != cpConfigFile {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				reload()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("config watcher error: %v", err)
+			case <-sigCh:
+				log.Println("received SIGHUP, reloading config")
+				reload()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// reload re-reads cpConfigFile, validates it, and only swaps the active
+// config and notifies subscribers on success. On failure it logs the
+// error and keeps serving the previously loaded config.
+func reload() {
+	var candidate ComputeNode
+	if _, err := toml.DecodeFile(cpConfigFile, &candidate); err != nil {
+		log.Printf("reload config failed, keeping previous config, path: %s, error: %v", cpConfigFile, err)
+		return
+	}
+	if err := applyOverlay(&candidate); err != nil {
+		log.Printf("reload config failed to apply env/secret overlay, keeping previous config, path: %s, error: %v", cpConfigFile, err)
+		return
+	}
+	if err := validate(&candidate, cpStandalone); err != nil {
+		log.Printf("reload config failed validation, keeping previous config, path: %s, error: %v", cpConfigFile, err)
+		return
+	}
+
+	old := GetConfig()
+	configRef.Store(&candidate)
+	buildChainRegistry(&candidate)
+	go CheckChainsHealth(5 * time.Second)
+	log.Printf("config reloaded from %s", cpConfigFile)
+
+	watcherMu.Lock()
+	handlers := append([]ChangeHandler(nil), subscribers...)
+	watcherMu.Unlock()
+	for _, handler := range handlers {
+		handler(old, &candidate)
+	}
+}